@@ -0,0 +1,114 @@
+// Package aggregate holds the in-memory graph the pipeline builds up as
+// parsed endpoints come in.
+package aggregate
+
+import (
+	"sync"
+
+	"github.com/Guizzs26/go-jsonplaceholder-aggregator/model"
+)
+
+type AggregatedData struct {
+	Users        map[int]model.User
+	Posts        map[int][]model.Post
+	Albums       map[int][]model.Album
+	Todos        map[int][]model.Todo
+	Comments     map[int][]model.Comment
+	Photos       map[int][]model.Photo
+	PostsByUser  map[int][]model.Post
+	AlbumsByUser map[int][]model.Album
+	mu           sync.RWMutex
+}
+
+func NewAggregatedData() *AggregatedData {
+	return &AggregatedData{
+		Users:        make(map[int]model.User),
+		Posts:        make(map[int][]model.Post),
+		Albums:       make(map[int][]model.Album),
+		Todos:        make(map[int][]model.Todo),
+		Comments:     make(map[int][]model.Comment),
+		Photos:       make(map[int][]model.Photo),
+		PostsByUser:  make(map[int][]model.Post),
+		AlbumsByUser: make(map[int][]model.Album),
+	}
+}
+
+func (ad *AggregatedData) AddUsers(users []model.User) {
+	ad.mu.Lock()
+	defer ad.mu.Unlock()
+	for _, u := range users {
+		ad.Users[u.ID] = u
+	}
+}
+
+func (ad *AggregatedData) AddPosts(posts []model.Post) {
+	ad.mu.Lock()
+	defer ad.mu.Unlock()
+	for _, p := range posts {
+		ad.Posts[p.UserID] = append(ad.Posts[p.UserID], p)
+		ad.PostsByUser[p.UserID] = append(ad.PostsByUser[p.UserID], p)
+	}
+}
+
+func (ad *AggregatedData) AddAlbums(albums []model.Album) {
+	ad.mu.Lock()
+	defer ad.mu.Unlock()
+	for _, a := range albums {
+		ad.Albums[a.UserID] = append(ad.Albums[a.UserID], a)
+		ad.AlbumsByUser[a.UserID] = append(ad.AlbumsByUser[a.UserID], a)
+	}
+}
+
+func (ad *AggregatedData) AddTodos(todos []model.Todo) {
+	ad.mu.Lock()
+	defer ad.mu.Unlock()
+	for _, t := range todos {
+		ad.Todos[t.UserID] = append(ad.Todos[t.UserID], t)
+	}
+}
+
+func (ad *AggregatedData) AddComments(comments []model.Comment) {
+	ad.mu.Lock()
+	defer ad.mu.Unlock()
+	for _, c := range comments {
+		ad.Comments[c.PostID] = append(ad.Comments[c.PostID], c)
+	}
+}
+
+func (ad *AggregatedData) AddPhotos(photos []model.Photo) {
+	ad.mu.Lock()
+	defer ad.mu.Unlock()
+	for _, p := range photos {
+		ad.Photos[p.AlbumID] = append(ad.Photos[p.AlbumID], p)
+	}
+}
+
+func (ad *AggregatedData) GetEnrichedUsers() []model.EnrichedUser {
+	ad.mu.RLock()
+	defer ad.mu.RUnlock()
+
+	var enriched []model.EnrichedUser
+	for _, user := range ad.Users {
+		commentCount := 0
+		for _, post := range ad.PostsByUser[user.ID] {
+			commentCount += len(ad.Comments[post.ID])
+		}
+
+		photoCount := 0
+		for _, album := range ad.AlbumsByUser[user.ID] {
+			photoCount += len(ad.Photos[album.ID])
+		}
+
+		enriched = append(enriched, model.EnrichedUser{
+			ID:           user.ID,
+			Name:         user.Name,
+			Email:        user.Email,
+			PostCount:    len(ad.Posts[user.ID]),
+			AlbumCount:   len(ad.Albums[user.ID]),
+			TodoCount:    len(ad.Todos[user.ID]),
+			CommentCount: commentCount,
+			PhotoCount:   photoCount,
+		})
+	}
+	return enriched
+}
@@ -0,0 +1,250 @@
+package aggregate
+
+import (
+	"sort"
+
+	"github.com/Guizzs26/go-jsonplaceholder-aggregator/model"
+)
+
+// Query is a fluent, read-only view over an AggregatedData snapshot. Each
+// filter/order method narrows or reorders the current set of users; Users
+// materializes the result.
+type Query struct {
+	ad    *AggregatedData
+	users []model.EnrichedUser
+}
+
+// Query starts a new query over the current state of ad. The snapshot is
+// taken immediately, so later writes to ad don't affect an in-flight Query.
+func (ad *AggregatedData) Query() *Query {
+	return &Query{ad: ad, users: ad.GetEnrichedUsers()}
+}
+
+func (q *Query) UsersWithMinPosts(min int) *Query {
+	filtered := make([]model.EnrichedUser, 0, len(q.users))
+	for _, u := range q.users {
+		if u.PostCount >= min {
+			filtered = append(filtered, u)
+		}
+	}
+	q.users = filtered
+	return q
+}
+
+// WithCompletedTodosAbove keeps users whose fraction of completed todos is
+// greater than ratio. Users with no todos are dropped, since they have no
+// completion ratio to compare.
+func (q *Query) WithCompletedTodosAbove(ratio float64) *Query {
+	filtered := make([]model.EnrichedUser, 0, len(q.users))
+	for _, u := range q.users {
+		completed, total := q.ad.todoCompletion(u.ID)
+		if total == 0 {
+			continue
+		}
+		if float64(completed)/float64(total) > ratio {
+			filtered = append(filtered, u)
+		}
+	}
+	q.users = filtered
+	return q
+}
+
+// OrderBy sorts the current users by an EnrichedUser int field, descending.
+// Unknown fields leave the order unchanged.
+func (q *Query) OrderBy(field string) *Query {
+	less, ok := enrichedUserFields[field]
+	if !ok {
+		return q
+	}
+	sort.SliceStable(q.users, func(i, j int) bool {
+		return less(q.users[i]) > less(q.users[j])
+	})
+	return q
+}
+
+// Limit caps the current users to the first n. A negative n clamps to 0
+// rather than panicking on the slice bound.
+func (q *Query) Limit(n int) *Query {
+	if n < 0 {
+		n = 0
+	}
+	if n < len(q.users) {
+		q.users = q.users[:n]
+	}
+	return q
+}
+
+// Users materializes the query, returning the users that survived every
+// filter in the order left by the last OrderBy (if any).
+func (q *Query) Users() []model.EnrichedUser {
+	return q.users
+}
+
+var enrichedUserFields = map[string]func(model.EnrichedUser) int{
+	"PostCount":    func(u model.EnrichedUser) int { return u.PostCount },
+	"AlbumCount":   func(u model.EnrichedUser) int { return u.AlbumCount },
+	"TodoCount":    func(u model.EnrichedUser) int { return u.TodoCount },
+	"CommentCount": func(u model.EnrichedUser) int { return u.CommentCount },
+	"PhotoCount":   func(u model.EnrichedUser) int { return u.PhotoCount },
+}
+
+func (ad *AggregatedData) todoCompletion(userID int) (completed, total int) {
+	ad.mu.RLock()
+	defer ad.mu.RUnlock()
+	for _, t := range ad.Todos[userID] {
+		total++
+		if t.Completed {
+			completed++
+		}
+	}
+	return completed, total
+}
+
+// Get walks the aggregated graph dynamically by a path of string keys and
+// int IDs, e.g. ad.Get("users", userID, "albums", albumID, "photos")
+// returns that album's photos, analogous to jsonq's path-based array access.
+func (ad *AggregatedData) Get(path ...any) []any {
+	ad.mu.RLock()
+	defer ad.mu.RUnlock()
+
+	if len(path) == 0 {
+		return nil
+	}
+
+	key, ok := path[0].(string)
+	if !ok || key != "users" {
+		return nil
+	}
+	return ad.getUsersPath(path[1:])
+}
+
+func (ad *AggregatedData) getUsersPath(path []any) []any {
+	if len(path) == 0 {
+		return usersToAny(ad.Users)
+	}
+
+	userID, ok := path[0].(int)
+	if !ok {
+		return nil
+	}
+	if len(path) == 1 {
+		if u, ok := ad.Users[userID]; ok {
+			return []any{u}
+		}
+		return nil
+	}
+
+	key, ok := path[1].(string)
+	if !ok {
+		return nil
+	}
+
+	switch key {
+	case "posts":
+		return ad.getPostsPath(userID, path[2:])
+	case "albums":
+		return ad.getAlbumsPath(userID, path[2:])
+	case "todos":
+		return todosToAny(ad.Todos[userID])
+	default:
+		return nil
+	}
+}
+
+func (ad *AggregatedData) getPostsPath(userID int, path []any) []any {
+	posts := ad.Posts[userID]
+	if len(path) == 0 {
+		return postsToAny(posts)
+	}
+
+	postID, ok := path[0].(int)
+	if !ok {
+		return nil
+	}
+	if len(path) == 1 {
+		for _, p := range posts {
+			if p.ID == postID {
+				return []any{p}
+			}
+		}
+		return nil
+	}
+
+	if key, ok := path[1].(string); ok && key == "comments" {
+		return commentsToAny(ad.Comments[postID])
+	}
+	return nil
+}
+
+func (ad *AggregatedData) getAlbumsPath(userID int, path []any) []any {
+	albums := ad.Albums[userID]
+	if len(path) == 0 {
+		return albumsToAny(albums)
+	}
+
+	albumID, ok := path[0].(int)
+	if !ok {
+		return nil
+	}
+	if len(path) == 1 {
+		for _, a := range albums {
+			if a.ID == albumID {
+				return []any{a}
+			}
+		}
+		return nil
+	}
+
+	if key, ok := path[1].(string); ok && key == "photos" {
+		return photosToAny(ad.Photos[albumID])
+	}
+	return nil
+}
+
+func usersToAny(users map[int]model.User) []any {
+	out := make([]any, 0, len(users))
+	for _, u := range users {
+		out = append(out, u)
+	}
+	return out
+}
+
+func postsToAny(posts []model.Post) []any {
+	out := make([]any, len(posts))
+	for i, p := range posts {
+		out[i] = p
+	}
+	return out
+}
+
+func albumsToAny(albums []model.Album) []any {
+	out := make([]any, len(albums))
+	for i, a := range albums {
+		out[i] = a
+	}
+	return out
+}
+
+func todosToAny(todos []model.Todo) []any {
+	out := make([]any, len(todos))
+	for i, t := range todos {
+		out[i] = t
+	}
+	return out
+}
+
+func commentsToAny(comments []model.Comment) []any {
+	out := make([]any, len(comments))
+	for i, c := range comments {
+		out[i] = c
+	}
+	return out
+}
+
+func photosToAny(photos []model.Photo) []any {
+	out := make([]any, len(photos))
+	for i, p := range photos {
+		out[i] = p
+	}
+	return out
+}
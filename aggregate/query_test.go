@@ -0,0 +1,84 @@
+package aggregate
+
+import (
+	"testing"
+
+	"github.com/Guizzs26/go-jsonplaceholder-aggregator/model"
+)
+
+func sampleData() *AggregatedData {
+	ad := NewAggregatedData()
+	ad.AddUsers([]model.User{{ID: 1, Name: "Alice"}, {ID: 2, Name: "Bob"}})
+	ad.AddPosts([]model.Post{{ID: 10, UserID: 1}, {ID: 11, UserID: 1}, {ID: 20, UserID: 2}})
+	ad.AddTodos([]model.Todo{
+		{ID: 1, UserID: 1, Completed: true},
+		{ID: 2, UserID: 1, Completed: false},
+		{ID: 3, UserID: 2, Completed: true},
+		{ID: 4, UserID: 2, Completed: true},
+	})
+	return ad
+}
+
+func TestQueryUsersWithMinPosts(t *testing.T) {
+	users := sampleData().Query().UsersWithMinPosts(2).Users()
+	if len(users) != 1 || users[0].ID != 1 {
+		t.Fatalf("UsersWithMinPosts(2) = %+v, want just user 1", users)
+	}
+}
+
+func TestQueryWithCompletedTodosAbove(t *testing.T) {
+	// User 1: 1/2 completed (0.5). User 2: 2/2 completed (1.0).
+	users := sampleData().Query().WithCompletedTodosAbove(0.5).Users()
+	if len(users) != 1 || users[0].ID != 2 {
+		t.Fatalf("WithCompletedTodosAbove(0.5) = %+v, want just user 2", users)
+	}
+}
+
+func TestQueryOrderByUnknownFieldLeavesOrderUnchanged(t *testing.T) {
+	q := sampleData().Query()
+	before := append([]model.EnrichedUser(nil), q.Users()...)
+	after := q.OrderBy("NotAField").Users()
+	if len(after) != len(before) {
+		t.Fatalf("OrderBy with unknown field changed length: %d vs %d", len(after), len(before))
+	}
+}
+
+func TestQueryLimitClampsNegativeInsteadOfPanicking(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("Limit(-1) panicked: %v", r)
+		}
+	}()
+
+	users := sampleData().Query().Limit(-1).Users()
+	if len(users) != 0 {
+		t.Fatalf("Limit(-1) = %d users, want 0", len(users))
+	}
+}
+
+func TestQueryLimitAboveCountIsNoop(t *testing.T) {
+	users := sampleData().Query().Limit(100).Users()
+	if len(users) != 2 {
+		t.Fatalf("Limit(100) = %d users, want all 2", len(users))
+	}
+}
+
+func TestGetPathWalksUsersPostsComments(t *testing.T) {
+	ad := sampleData()
+	ad.AddComments([]model.Comment{{ID: 100, PostID: 10}})
+
+	got := ad.Get("users", 1, "posts", 10, "comments")
+	if len(got) != 1 {
+		t.Fatalf("Get(users, 1, posts, 10, comments) = %v, want 1 comment", got)
+	}
+	c, ok := got[0].(model.Comment)
+	if !ok || c.ID != 100 {
+		t.Fatalf("unexpected comment returned: %+v", got[0])
+	}
+}
+
+func TestGetPathUnknownRootReturnsNil(t *testing.T) {
+	if got := sampleData().Get("not-users"); got != nil {
+		t.Fatalf("Get with unknown root = %v, want nil", got)
+	}
+}
@@ -0,0 +1,390 @@
+// Package aggregator wires together the fetch, parse, and aggregate stages
+// into a reusable pipeline. New jsonplaceholder-style endpoints, or entirely
+// different REST resources, can be added with Register instead of editing a
+// hard-coded switch statement.
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Guizzs26/go-jsonplaceholder-aggregator/aggregate"
+	"github.com/Guizzs26/go-jsonplaceholder-aggregator/deadline"
+	"github.com/Guizzs26/go-jsonplaceholder-aggregator/fetcher"
+	"github.com/Guizzs26/go-jsonplaceholder-aggregator/model"
+	"github.com/Guizzs26/go-jsonplaceholder-aggregator/parser"
+)
+
+const (
+	GetUsers    = "https://jsonplaceholder.typicode.com/users"
+	GetPosts    = "https://jsonplaceholder.typicode.com/posts"
+	GetComments = "https://jsonplaceholder.typicode.com/comments"
+	GetAlbums   = "https://jsonplaceholder.typicode.com/albums"
+	GetPhotos   = "https://jsonplaceholder.typicode.com/photos"
+	GetTodos    = "https://jsonplaceholder.typicode.com/todos"
+
+	DefaultParseWorkers = 6
+)
+
+// endpointReg is how an endpoint is parsed and folded into an
+// aggregate.AggregatedData, looked up by URL instead of a type switch.
+type endpointReg struct {
+	typeName string
+	parseFn  func([]byte) (any, error)
+	addFn    func(*aggregate.AggregatedData, any)
+}
+
+// ProgressFunc is notified once per completed fetch and once per parsed
+// batch, so a caller can drive a progress bar without instrumenting the
+// pipeline itself.
+type ProgressFunc func(stage, endpoint string)
+
+// Aggregator runs the fetch -> parse -> aggregate pipeline over a
+// registry of endpoints.
+type Aggregator struct {
+	fetcher      fetcher.Fetcher
+	parseWorkers int
+
+	mu       sync.RWMutex
+	registry map[string]endpointReg
+	order    []string
+	progress ProgressFunc
+
+	fetchDeadline     *deadline.Timer
+	parseDeadline     *deadline.Timer
+	aggregateDeadline *deadline.Timer
+}
+
+// New creates an Aggregator with the six jsonplaceholder endpoints already
+// registered, using f to fetch raw bytes.
+func New(f fetcher.Fetcher) *Aggregator {
+	ag := &Aggregator{
+		fetcher:      f,
+		parseWorkers: DefaultParseWorkers,
+		registry:     make(map[string]endpointReg),
+
+		fetchDeadline:     deadline.New(),
+		parseDeadline:     deadline.New(),
+		aggregateDeadline: deadline.New(),
+	}
+
+	ag.Register(GetUsers, "user", parser.Decode[model.User], func(ad *aggregate.AggregatedData, v any) {
+		ad.AddUsers(v.([]model.User))
+	})
+	ag.Register(GetPosts, "post", parser.Decode[model.Post], func(ad *aggregate.AggregatedData, v any) {
+		ad.AddPosts(v.([]model.Post))
+	})
+	ag.Register(GetComments, "comment", parser.Decode[model.Comment], func(ad *aggregate.AggregatedData, v any) {
+		ad.AddComments(v.([]model.Comment))
+	})
+	ag.Register(GetAlbums, "album", parser.Decode[model.Album], func(ad *aggregate.AggregatedData, v any) {
+		ad.AddAlbums(v.([]model.Album))
+	})
+	ag.Register(GetPhotos, "photo", parser.Decode[model.Photo], func(ad *aggregate.AggregatedData, v any) {
+		ad.AddPhotos(v.([]model.Photo))
+	})
+	ag.Register(GetTodos, "todo", parser.Decode[model.Todo], func(ad *aggregate.AggregatedData, v any) {
+		ad.AddTodos(v.([]model.Todo))
+	})
+
+	return ag
+}
+
+// Register adds or replaces the endpoint handled at url: parseFn decodes the
+// raw response bytes, and addFn folds the decoded value into an
+// aggregate.AggregatedData. typeName labels the endpoint in ParsedData and
+// PipelineError.
+func (ag *Aggregator) Register(url, typeName string, parseFn func([]byte) (any, error), addFn func(*aggregate.AggregatedData, any)) {
+	ag.mu.Lock()
+	defer ag.mu.Unlock()
+
+	if _, exists := ag.registry[url]; !exists {
+		ag.order = append(ag.order, url)
+	}
+	ag.registry[url] = endpointReg{typeName: typeName, parseFn: parseFn, addFn: addFn}
+}
+
+func (ag *Aggregator) endpoints() []string {
+	ag.mu.RLock()
+	defer ag.mu.RUnlock()
+	return append([]string(nil), ag.order...)
+}
+
+// EndpointCount returns the number of endpoints currently registered.
+func (ag *Aggregator) EndpointCount() int {
+	ag.mu.RLock()
+	defer ag.mu.RUnlock()
+	return len(ag.order)
+}
+
+func (ag *Aggregator) lookup(url string) (endpointReg, bool) {
+	ag.mu.RLock()
+	defer ag.mu.RUnlock()
+	reg, ok := ag.registry[url]
+	return reg, ok
+}
+
+// OnProgress registers fn to be called once per completed fetch and once per
+// parsed batch during Run/RunStream. Set it before calling Run/RunStream;
+// changing it mid-run is not supported.
+func (ag *Aggregator) OnProgress(fn ProgressFunc) {
+	ag.mu.Lock()
+	defer ag.mu.Unlock()
+	ag.progress = fn
+}
+
+func (ag *Aggregator) notify(stage, endpoint string) {
+	ag.mu.RLock()
+	fn := ag.progress
+	ag.mu.RUnlock()
+	if fn != nil {
+		fn(stage, endpoint)
+	}
+}
+
+// SetFetchDeadline bounds how long the fetch stage of Run/RunStream may run.
+// Once t passes, in-flight fetches are abandoned and a PipelineError{Stage:
+// "fetch", Error: model.ErrDeadlineExceeded} is reported. A zero t disarms
+// the deadline, letting the stage run unbounded.
+func (ag *Aggregator) SetFetchDeadline(t time.Time) {
+	ag.fetchDeadline.SetDeadline(t)
+}
+
+// SetParseDeadline bounds how long the parse stage of Run/RunStream may run,
+// the same way SetFetchDeadline bounds the fetch stage.
+func (ag *Aggregator) SetParseDeadline(t time.Time) {
+	ag.parseDeadline.SetDeadline(t)
+}
+
+// SetAggregateDeadline bounds how long Run may spend folding parsed data
+// into the resulting aggregate.AggregatedData.
+func (ag *Aggregator) SetAggregateDeadline(t time.Time) {
+	ag.aggregateDeadline.SetDeadline(t)
+}
+
+// watchDeadline cancels cancel and reports a deadline-exceeded error for
+// stage as soon as d fires, unless done closes first. It joins wg so a
+// caller can wait for the (possible) errCh send to happen before closing
+// errCh.
+func watchDeadline(wg *sync.WaitGroup, d *deadline.Timer, stage string, cancel context.CancelFunc, done <-chan struct{}, errCh chan<- model.PipelineError) {
+	defer wg.Done()
+	select {
+	case <-d.C():
+		cancel()
+		errCh <- model.PipelineError{
+			Stage:     stage,
+			Error:     model.ErrDeadlineExceeded,
+			Kind:      model.ErrKindTimeout,
+			Timestamp: time.Now(),
+		}
+	case <-done:
+	}
+}
+
+// RunStream drives the fetch and parse stages for every registered endpoint
+// and streams results as they become ready, without waiting for the whole
+// pipeline to finish. Callers that want the final aggregated graph should
+// use Run instead.
+func (ag *Aggregator) RunStream(ctx context.Context) (<-chan model.ParsedData, <-chan model.PipelineError) {
+	endpoints := ag.endpoints()
+
+	rawCh := make(chan model.RawResponse, len(endpoints))
+	parsedCh := make(chan model.ParsedData, len(endpoints))
+	errCh := make(chan model.PipelineError, len(endpoints)*2)
+
+	// If the underlying fetcher can push stale-while-revalidate refreshes
+	// (e.g. a fetcher.CachedFetcher), point them at this run's channels so a
+	// background refresh flows through parse/aggregate like any other fetch.
+	// refreshWg tracks refreshes spawned under this run so the closing
+	// goroutine below can wait for them before closing rawCh: a refresh can
+	// still be in flight after fetchWg is done, and sending on a channel
+	// after it's closed panics.
+	var refreshWg sync.WaitGroup
+	if rw, ok := ag.fetcher.(fetcher.RefreshWirer); ok {
+		rw.WithRefreshChannels(rawCh, errCh, &refreshWg)
+	}
+
+	fetchCtx, cancelFetch := context.WithCancel(ctx)
+	fetchDone := make(chan struct{})
+	var fetchWatchWg sync.WaitGroup
+	fetchWatchWg.Add(1)
+	go watchDeadline(&fetchWatchWg, ag.fetchDeadline, "fetch", cancelFetch, fetchDone, errCh)
+
+	var fetchWg sync.WaitGroup
+	fetchWg.Add(len(endpoints))
+	for _, e := range endpoints {
+		go func(endpoint string) {
+			fetcher.FetchInto(fetchCtx, ag.fetcher, endpoint, rawCh, errCh, &fetchWg, "fetch")
+			ag.notify("fetch", endpoint)
+		}(e)
+	}
+	go func() {
+		fetchWg.Wait()
+		close(fetchDone)
+		fetchWatchWg.Wait()
+		cancelFetch()
+		refreshWg.Wait()
+		close(rawCh)
+	}()
+
+	parseCtx, cancelParse := context.WithCancel(ctx)
+	parseDone := make(chan struct{})
+	var parseWatchWg sync.WaitGroup
+	parseWatchWg.Add(1)
+	go watchDeadline(&parseWatchWg, ag.parseDeadline, "parse", cancelParse, parseDone, errCh)
+
+	var parseWg sync.WaitGroup
+	parseWg.Add(ag.parseWorkers)
+	for range ag.parseWorkers {
+		go func() {
+			defer parseWg.Done()
+			for {
+				select {
+				case raw, ok := <-rawCh:
+					if !ok {
+						return
+					}
+					ag.parseOne(parseCtx, raw, parsedCh, errCh)
+				case <-parseCtx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		parseWg.Wait()
+		close(parseDone)
+		parseWatchWg.Wait()
+		cancelParse()
+		close(parsedCh)
+		close(errCh)
+	}()
+
+	return parsedCh, errCh
+}
+
+// parseOne decodes raw and either forwards it on parsedCh or reports a
+// parse-stage PipelineError, honoring ctx so a deadline-cancelled parse
+// stage doesn't block on a send nobody will read.
+func (ag *Aggregator) parseOne(ctx context.Context, raw model.RawResponse, parsedCh chan<- model.ParsedData, errCh chan<- model.PipelineError) {
+	reg, ok := ag.lookup(raw.Endpoint)
+	if !ok {
+		select {
+		case errCh <- model.PipelineError{
+			Stage:     "parse",
+			Endpoint:  raw.Endpoint,
+			Error:     fmt.Errorf("no parser registered for endpoint %q", raw.Endpoint),
+			Timestamp: time.Now(),
+		}:
+		case <-ctx.Done():
+		}
+		return
+	}
+
+	data, err := reg.parseFn(raw.Data)
+	if err != nil {
+		select {
+		case errCh <- model.PipelineError{
+			Stage:     "parse",
+			Endpoint:  raw.Endpoint,
+			Error:     err,
+			Kind:      model.ErrKindDecode,
+			Timestamp: time.Now(),
+		}:
+		case <-ctx.Done():
+		}
+		return
+	}
+
+	select {
+	case parsedCh <- model.ParsedData{Data: data, Endpoint: raw.Endpoint, Type: reg.typeName}:
+		ag.notify("parse", raw.Endpoint)
+	case <-ctx.Done():
+	}
+}
+
+// Fold applies a single parsed batch to ad using the addFn registered for
+// parsed.Endpoint, reporting whether one was found. It's the same folding
+// Run does internally; callers driving RunStream directly (e.g. to stream
+// results before the whole pipeline finishes) can use it to build their own
+// aggregate.AggregatedData incrementally.
+func (ag *Aggregator) Fold(ad *aggregate.AggregatedData, parsed model.ParsedData) bool {
+	reg, ok := ag.lookup(parsed.Endpoint)
+	if !ok {
+		return false
+	}
+	reg.addFn(ad, parsed.Data)
+	return true
+}
+
+// Run drives the full pipeline to completion and returns the resulting
+// aggregate.AggregatedData. Errors encountered along the way are logged by
+// the caller via the returned error, which reports only the first one seen;
+// use RunStream directly to inspect every error.
+func (ag *Aggregator) Run(ctx context.Context) (*aggregate.AggregatedData, error) {
+	// Run owns a child context so that an aggregate-stage deadline can cancel
+	// the fetch and parse stages beneath it too: otherwise they'd keep
+	// producing into parsedCh/errCh with nobody left reading.
+	pipelineCtx, cancelPipeline := context.WithCancel(ctx)
+	defer cancelPipeline()
+
+	parsedCh, errCh := ag.RunStream(pipelineCtx)
+	aggregated := aggregate.NewAggregatedData()
+
+	var firstErr error
+	var errWg sync.WaitGroup
+	errWg.Add(1)
+	go func() {
+		defer errWg.Done()
+		for err := range errCh {
+			if firstErr == nil {
+				if err.Endpoint == "" {
+					firstErr = fmt.Errorf("%s: %w", err.Stage, err.Error)
+				} else {
+					firstErr = fmt.Errorf("%s %s: %w", err.Stage, err.Endpoint, err.Error)
+				}
+			}
+		}
+	}()
+
+	// deadlineHit is set only if the aggregate-stage deadline itself is what
+	// cut the loop short, as opposed to pipelineCtx's parent ctx (e.g. the
+	// caller cancelling, or a fetch/parse deadline that happened to also
+	// stop the aggregate loop by draining parsedCh empty).
+	var deadlineHit bool
+	aggDone := make(chan struct{})
+	var aggWatchWg sync.WaitGroup
+	aggWatchWg.Add(1)
+	go func() {
+		defer aggWatchWg.Done()
+		select {
+		case <-ag.aggregateDeadline.C():
+			deadlineHit = true
+			cancelPipeline()
+		case <-aggDone:
+		}
+	}()
+
+loop:
+	for {
+		select {
+		case parsed, ok := <-parsedCh:
+			if !ok {
+				break loop
+			}
+			ag.Fold(aggregated, parsed)
+		case <-pipelineCtx.Done():
+			break loop
+		}
+	}
+	close(aggDone)
+	aggWatchWg.Wait()
+
+	errWg.Wait()
+	if firstErr == nil && deadlineHit {
+		firstErr = fmt.Errorf("aggregate: %w", model.ErrDeadlineExceeded)
+	}
+	return aggregated, firstErr
+}
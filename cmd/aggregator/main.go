@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	aggregator "github.com/Guizzs26/go-jsonplaceholder-aggregator"
+	"github.com/Guizzs26/go-jsonplaceholder-aggregator/fetcher"
+	"github.com/Guizzs26/go-jsonplaceholder-aggregator/server"
+)
+
+const (
+	requestTimeout = 2 * time.Second
+
+	rateLimitPerSecond = 10
+	rateLimitBurst     = 10
+
+	cacheDir = ".cache"
+
+	shutdownTimeout = 5 * time.Second
+
+	// Stage budgets for the whole pipeline run, not a single request: fetch
+	// covers every endpoint fetched concurrently, parse covers decoding
+	// everything fetched so far.
+	fetchStageTimeout = 5 * time.Second
+	parseStageTimeout = 2 * time.Second
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		runServe(ctx, os.Args[2:])
+		return
+	}
+	runCLI(ctx, os.Args[1:])
+}
+
+// newFetcher builds the HTTP fetcher shared by both the CLI and serve modes:
+// rate-limited, retrying, and cached to disk.
+func newFetcher() fetcher.Fetcher {
+	limiter := fetcher.NewRateLimiter(rateLimitPerSecond, rateLimitBurst)
+	httpFetcher := fetcher.NewHTTPFetcher(&http.Client{Timeout: requestTimeout}, fetcher.DefaultRetryPolicy, limiter)
+
+	var cacheBackend fetcher.CacheBackend
+	if disk, err := fetcher.NewDiskCache(cacheDir); err != nil {
+		log.Printf("cache: falling back to in-memory backend: %v", err)
+		cacheBackend = fetcher.NewMemoryCache()
+	} else {
+		cacheBackend = disk
+	}
+	return fetcher.NewCachedFetcher(httpFetcher, cacheBackend, fetcher.DefaultCacheTTL, fetcher.DefaultCacheStaleWindow)
+}
+
+func runServe(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "address to listen on")
+	fs.Parse(args)
+
+	ag := aggregator.New(newFetcher())
+	srv := &http.Server{Addr: *addr, Handler: server.New(ag).Handler()}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("serve: shutdown: %v", err)
+		}
+	}()
+
+	log.Printf("serving on %s", *addr)
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Fatalf("serve: %v", err)
+	}
+}
+
+func runCLI(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("aggregator", flag.ExitOnError)
+	silent := fs.Bool("silent", false, "suppress progress output")
+	noProgress := fs.Bool("no-progress", false, "alias for --silent")
+	fetchTimeout := fs.Duration("fetch-timeout", fetchStageTimeout, "budget for the whole fetch stage")
+	parseTimeout := fs.Duration("parse-timeout", parseStageTimeout, "budget for the whole parse stage")
+	fs.Parse(args)
+
+	ag := aggregator.New(newFetcher())
+	if *fetchTimeout > 0 {
+		ag.SetFetchDeadline(time.Now().Add(*fetchTimeout))
+	}
+	if *parseTimeout > 0 {
+		ag.SetParseDeadline(time.Now().Add(*parseTimeout))
+	}
+
+	bar := newProgressBar(os.Stderr, ag.EndpointCount()*2, *silent || *noProgress)
+	ag.OnProgress(bar.tick)
+
+	aggregated, err := ag.Run(ctx)
+	bar.done()
+	if err != nil {
+		log.Printf("pipeline finished with errors: %v", err)
+	}
+
+	enrichedUsers := aggregated.GetEnrichedUsers()
+
+	fmt.Println("===== Aggregated Data =====")
+	for _, enriched := range enrichedUsers {
+		fmt.Printf("User: %-20s | Posts: %d | Comments: %d | Albums: %d | Photos: %d | Todos: %d\n",
+			enriched.Name, enriched.PostCount, enriched.CommentCount, enriched.AlbumCount, enriched.PhotoCount, enriched.TodoCount)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// progressBar prints one tick per completed fetch/parse batch, mirroring a
+// pb-style CLI progress indicator. It's silenced entirely when silent is set.
+type progressBar struct {
+	mu     sync.Mutex
+	out    io.Writer
+	total  int
+	ticks  int
+	silent bool
+}
+
+func newProgressBar(out io.Writer, total int, silent bool) *progressBar {
+	return &progressBar{out: out, total: total, silent: silent}
+}
+
+func (p *progressBar) tick(stage, endpoint string) {
+	if p.silent {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ticks++
+	fmt.Fprintf(p.out, "\r[%-20s] %d/%d %s %s", strings.Repeat("=", p.ticks%21), p.ticks, p.total, stage, endpoint)
+}
+
+func (p *progressBar) done() {
+	if p.silent {
+		return
+	}
+	fmt.Fprintln(p.out)
+}
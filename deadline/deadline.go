@@ -0,0 +1,72 @@
+// Package deadline provides a per-stage cancellation primitive: a timer
+// that closes a channel once armed and its deadline passes, similar to the
+// deadlineTimer used internally by the net package.
+package deadline
+
+import (
+	"sync"
+	"time"
+)
+
+// Timer closes its signal channel once the time passed to SetDeadline has
+// elapsed. The zero value is usable and starts disarmed. Like
+// net.Conn.SetDeadline, it's meant to be reused across many operations: a
+// fired Timer can be rearmed with another SetDeadline call and will fire
+// again at the new deadline.
+type Timer struct {
+	mu     sync.Mutex
+	timer  *time.Timer
+	cancel chan struct{}
+	fired  bool
+	gen    uint64
+}
+
+func New() *Timer {
+	return &Timer{cancel: make(chan struct{})}
+}
+
+// SetDeadline arms the timer to fire at t, replacing any previously armed
+// deadline - including one that already fired, which gets a fresh signal
+// channel so C() reports "armed" rather than "still exceeded" from a
+// previous round. A zero t disarms it without firing.
+func (d *Timer) SetDeadline(t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	// Bump the generation so a fire callback from the timer we just Stopped
+	// - which can still run once if it had already started before Stop
+	// returned - finds it's stale and no-ops instead of closing this new
+	// generation's channel early.
+	d.gen++
+	gen := d.gen
+	d.cancel = make(chan struct{})
+	d.fired = false
+
+	if t.IsZero() {
+		d.timer = nil
+		return
+	}
+	d.timer = time.AfterFunc(time.Until(t), func() { d.fire(gen) })
+}
+
+func (d *Timer) fire(gen uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.fired || gen != d.gen {
+		return
+	}
+	d.fired = true
+	close(d.cancel)
+}
+
+// C returns the channel that's closed once the current deadline passes.
+// Call it fresh after each SetDeadline that might rearm the Timer: a
+// reference captured before a rearm keeps reporting the previous round.
+func (d *Timer) C() <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.cancel
+}
@@ -0,0 +1,75 @@
+package deadline
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimerFiresAtDeadline(t *testing.T) {
+	d := New()
+	d.SetDeadline(time.Now().Add(20 * time.Millisecond))
+
+	select {
+	case <-d.C():
+	case <-time.After(time.Second):
+		t.Fatal("Timer did not fire within 1s of a 20ms deadline")
+	}
+}
+
+func TestZeroTimerNeverFires(t *testing.T) {
+	d := New()
+
+	select {
+	case <-d.C():
+		t.Fatal("unarmed Timer fired")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestSetDeadlineZeroDisarms(t *testing.T) {
+	d := New()
+	d.SetDeadline(time.Now().Add(10 * time.Millisecond))
+	d.SetDeadline(time.Time{})
+
+	select {
+	case <-d.C():
+		t.Fatal("Timer fired after being disarmed")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestSetDeadlineReplacesPreviousOne(t *testing.T) {
+	d := New()
+	d.SetDeadline(time.Now().Add(time.Hour))
+	d.SetDeadline(time.Now().Add(10 * time.Millisecond))
+
+	select {
+	case <-d.C():
+	case <-time.After(time.Second):
+		t.Fatal("replacing the deadline with a sooner one did not take effect")
+	}
+}
+
+// TestTimerRearmsAfterFiring covers reuse across many stages/runs on the
+// same Timer (e.g. Aggregator.fetchDeadline across repeated Run calls):
+// once a Timer has fired, a later SetDeadline must produce a Timer that
+// waits for the new deadline again instead of reporting "already exceeded"
+// forever.
+func TestTimerRearmsAfterFiring(t *testing.T) {
+	d := New()
+	d.SetDeadline(time.Now().Add(10 * time.Millisecond))
+
+	select {
+	case <-d.C():
+	case <-time.After(time.Second):
+		t.Fatal("Timer never fired the first time")
+	}
+
+	d.SetDeadline(time.Now().Add(time.Hour))
+
+	select {
+	case <-d.C():
+		t.Fatal("rearmed Timer reported deadline-exceeded immediately instead of waiting an hour")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
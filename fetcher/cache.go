@@ -0,0 +1,215 @@
+package fetcher
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Guizzs26/go-jsonplaceholder-aggregator/model"
+)
+
+const (
+	DefaultCacheTTL         = time.Hour
+	DefaultCacheStaleWindow = 10 * time.Minute
+)
+
+// CacheEntry holds the raw bytes returned by an endpoint plus the time they
+// were fetched, so callers can decide whether the entry is fresh, stale but
+// still usable, or expired.
+type CacheEntry struct {
+	Data      []byte
+	FetchedAt time.Time
+}
+
+func (e CacheEntry) expired(ttl time.Duration) bool {
+	return time.Since(e.FetchedAt) > ttl
+}
+
+// stale reports whether an entry has passed its TTL but is still within the
+// stale-while-revalidate window, where it can be served immediately while a
+// refresh happens in the background.
+func (e CacheEntry) stale(ttl, staleWindow time.Duration) bool {
+	age := time.Since(e.FetchedAt)
+	return age > ttl && age <= ttl+staleWindow
+}
+
+// CacheBackend is the storage used by CachedFetcher, keyed by endpoint URL.
+type CacheBackend interface {
+	Get(key string) (CacheEntry, bool)
+	Set(key string, entry CacheEntry) error
+}
+
+// MemoryCache is an in-memory CacheBackend, useful as a default or in tests.
+type MemoryCache struct {
+	mu      sync.RWMutex
+	entries map[string]CacheEntry
+}
+
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]CacheEntry)}
+}
+
+func (c *MemoryCache) Get(key string) (CacheEntry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entry, ok := c.entries[key]
+	return entry, ok
+}
+
+func (c *MemoryCache) Set(key string, entry CacheEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry
+	return nil
+}
+
+// DiskCache persists entries as JSON files under a directory, one file per
+// endpoint, so re-running the aggregator during development doesn't hammer
+// jsonplaceholder.
+type DiskCache struct {
+	dir string
+	mu  sync.Mutex
+}
+
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+func (c *DiskCache) pathFor(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (c *DiskCache) Get(key string) (CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	raw, err := os.ReadFile(c.pathFor(key))
+	if err != nil {
+		return CacheEntry{}, false
+	}
+
+	var entry CacheEntry
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return CacheEntry{}, false
+	}
+	return entry, true
+}
+
+func (c *DiskCache) Set(key string, entry CacheEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.pathFor(key), raw, 0o644)
+}
+
+// CachedFetcher wraps a Fetcher with a TTL cache. Fresh entries are served
+// directly; stale entries (past TTL but within staleWindow) are served
+// immediately while a refresh runs in the background.
+type CachedFetcher struct {
+	fetcher     Fetcher
+	backend     CacheBackend
+	ttl         time.Duration
+	staleWindow time.Duration
+
+	mu           sync.Mutex
+	refreshCh    chan<- model.RawResponse
+	refreshErrCh chan<- model.PipelineError
+	refreshWg    *sync.WaitGroup
+}
+
+func NewCachedFetcher(fetcher Fetcher, backend CacheBackend, ttl, staleWindow time.Duration) *CachedFetcher {
+	return &CachedFetcher{fetcher: fetcher, backend: backend, ttl: ttl, staleWindow: staleWindow}
+}
+
+// WithRefreshChannels wires background stale-while-revalidate refreshes into
+// the same rawCh/errCh the fetch stage already drains, so a refreshed
+// endpoint flows through the normal parse/aggregate path like any other
+// fetch. wg is the caller's run-scoped tracker for outstanding refreshes: a
+// revalidate started under this run calls wg.Add(1)/Done() around its send,
+// so a caller (e.g. RunStream) can Wait() on it before closing ch/errCh
+// instead of racing a background send against a closed channel. It
+// implements fetcher.RefreshWirer; RunStream calls it on every run so the
+// channels and tracker stay current even though they're recreated per run.
+func (c *CachedFetcher) WithRefreshChannels(ch chan<- model.RawResponse, errCh chan<- model.PipelineError, wg *sync.WaitGroup) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refreshCh = ch
+	c.refreshErrCh = errCh
+	c.refreshWg = wg
+}
+
+func (c *CachedFetcher) Fetch(ctx context.Context, endpoint string) ([]byte, error) {
+	if entry, ok := c.backend.Get(endpoint); ok {
+		if !entry.expired(c.ttl) {
+			return entry.Data, nil
+		}
+		if entry.stale(c.ttl, c.staleWindow) {
+			// Capture the channels and tracker together, at the moment this
+			// refresh is spawned, so a later WithRefreshChannels call from a
+			// new run can't hand this goroutine a wg that doesn't match the
+			// channels it ends up sending on.
+			c.mu.Lock()
+			refreshCh, refreshErrCh, refreshWg := c.refreshCh, c.refreshErrCh, c.refreshWg
+			if refreshWg != nil {
+				refreshWg.Add(1)
+			}
+			c.mu.Unlock()
+			go c.revalidate(endpoint, refreshCh, refreshErrCh, refreshWg)
+			return entry.Data, nil
+		}
+	}
+
+	data, err := c.fetcher.Fetch(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.backend.Set(endpoint, CacheEntry{Data: data, FetchedAt: time.Now()}); err != nil {
+		log.Printf("cache: failed to persist entry for %s: %v", endpoint, err)
+	}
+
+	return data, nil
+}
+
+func (c *CachedFetcher) revalidate(endpoint string, refreshCh chan<- model.RawResponse, refreshErrCh chan<- model.PipelineError, refreshWg *sync.WaitGroup) {
+	if refreshWg != nil {
+		defer refreshWg.Done()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeoutForRevalidate)
+	defer cancel()
+
+	data, err := c.fetcher.Fetch(ctx, endpoint)
+	if err != nil {
+		if refreshErrCh != nil {
+			refreshErrCh <- model.PipelineError{Stage: "fetch", Endpoint: endpoint, Error: err, Timestamp: time.Now()}
+		}
+		return
+	}
+
+	if err := c.backend.Set(endpoint, CacheEntry{Data: data, FetchedAt: time.Now()}); err != nil {
+		log.Printf("cache: failed to persist refreshed entry for %s: %v", endpoint, err)
+	}
+
+	if refreshCh != nil {
+		refreshCh <- model.RawResponse{Endpoint: endpoint, Data: data}
+	}
+}
+
+// requestTimeoutForRevalidate bounds a single background refresh so a slow
+// endpoint can't pile up goroutines across repeated stale hits.
+const requestTimeoutForRevalidate = 2 * time.Second
@@ -0,0 +1,129 @@
+package fetcher
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Guizzs26/go-jsonplaceholder-aggregator/model"
+)
+
+func TestMemoryCacheGetSet(t *testing.T) {
+	c := NewMemoryCache()
+	if _, ok := c.Get("missing"); ok {
+		t.Fatal("Get on empty cache returned ok=true")
+	}
+
+	entry := CacheEntry{Data: []byte("payload"), FetchedAt: time.Now()}
+	if err := c.Set("k", entry); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	got, ok := c.Get("k")
+	if !ok || string(got.Data) != "payload" {
+		t.Fatalf("Get after Set = %+v, %v", got, ok)
+	}
+}
+
+func TestCachedFetcherServesFreshEntryWithoutCallingFetcher(t *testing.T) {
+	m := &mockFetcher{responses: [][]byte{[]byte("fresh")}}
+	backend := NewMemoryCache()
+	backend.Set("ep", CacheEntry{Data: []byte("fresh"), FetchedAt: time.Now()})
+
+	cf := NewCachedFetcher(m, backend, time.Hour, 10*time.Minute)
+	data, err := cf.Fetch(context.Background(), "ep")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if string(data) != "fresh" {
+		t.Errorf("Fetch = %q, want %q", data, "fresh")
+	}
+	if m.calls != 0 {
+		t.Errorf("underlying fetcher called %d times, want 0 for a fresh entry", m.calls)
+	}
+}
+
+// TestCachedFetcherRefreshWirerPushesRevalidatedData exercises the wiring
+// fixed in newFetcher/Aggregator.RunStream: once WithRefreshChannels is set,
+// a stale-while-revalidate hit should eventually push the refreshed bytes
+// onto the supplied channel instead of only updating the cache silently.
+func TestCachedFetcherRefreshWirerPushesRevalidatedData(t *testing.T) {
+	backend := NewMemoryCache()
+	staleAt := time.Now().Add(-2 * time.Minute)
+	backend.Set("ep", CacheEntry{Data: []byte("stale"), FetchedAt: staleAt})
+
+	m := &mockFetcher{responses: [][]byte{[]byte("revalidated")}}
+	cf := NewCachedFetcher(m, backend, time.Minute, 10*time.Minute)
+
+	rawCh := make(chan model.RawResponse, 1)
+	errCh := make(chan model.PipelineError, 1)
+	var wg sync.WaitGroup
+	var wirer RefreshWirer = cf
+	wirer.WithRefreshChannels(rawCh, errCh, &wg)
+
+	data, err := cf.Fetch(context.Background(), "ep")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if string(data) != "stale" {
+		t.Fatalf("Fetch on a stale entry = %q, want the stale value served immediately", data)
+	}
+
+	select {
+	case raw := <-rawCh:
+		if string(raw.Data) != "revalidated" {
+			t.Errorf("refreshed RawResponse = %q, want %q", raw.Data, "revalidated")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("background revalidate never pushed onto rawCh")
+	}
+}
+
+func TestCachedFetcherImplementsRefreshWirer(t *testing.T) {
+	var _ RefreshWirer = (*CachedFetcher)(nil)
+}
+
+// TestCachedFetcherRefreshWgGuardsAgainstClosedChannel reproduces the race
+// fixed alongside the RefreshWirer wg parameter: a caller that waits on the
+// wg (as Aggregator.RunStream now does) before closing ch/errCh must never
+// observe a send on an already-closed channel, even when the background
+// revalidate is slower than the rest of the run.
+func TestCachedFetcherRefreshWgGuardsAgainstClosedChannel(t *testing.T) {
+	backend := NewMemoryCache()
+	staleAt := time.Now().Add(-2 * time.Minute)
+	backend.Set("ep", CacheEntry{Data: []byte("stale"), FetchedAt: staleAt})
+
+	slow := &mockFetcher{responses: [][]byte{[]byte("revalidated")}, delay: 50 * time.Millisecond}
+	cf := NewCachedFetcher(slow, backend, time.Minute, 10*time.Minute)
+
+	rawCh := make(chan model.RawResponse, 1)
+	errCh := make(chan model.PipelineError, 1)
+	var wg sync.WaitGroup
+	cf.WithRefreshChannels(rawCh, errCh, &wg)
+
+	if _, err := cf.Fetch(context.Background(), "ep"); err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	// Mirrors RunStream's close sequence: wait for outstanding refreshes
+	// before closing, instead of closing as soon as the stage's own
+	// goroutines are done.
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(rawCh)
+		close(errCh)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("wg.Wait() never returned; revalidate goroutine leaked")
+	}
+
+	if raw, ok := <-rawCh; !ok || string(raw.Data) != "revalidated" {
+		t.Fatalf("rawCh after close = %+v, %v; want the revalidated response drained first", raw, ok)
+	}
+}
@@ -0,0 +1,272 @@
+// Package fetcher provides the Fetcher abstraction used to retrieve raw
+// bytes for an endpoint, along with the default HTTP implementation and its
+// retry, backoff, and rate-limiting behavior.
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Guizzs26/go-jsonplaceholder-aggregator/model"
+)
+
+const (
+	DefaultMaxRetries = 3
+	DefaultBaseDelay  = 200 * time.Millisecond
+	DefaultMaxDelay   = 5 * time.Second
+)
+
+// Fetcher abstracts how raw bytes are retrieved for an endpoint, so the
+// pipeline can be driven by mocks in tests without hitting the network.
+type Fetcher interface {
+	Fetch(ctx context.Context, endpoint string) ([]byte, error)
+}
+
+// RefreshWirer is implemented by a Fetcher that can push background
+// refreshes (e.g. CachedFetcher's stale-while-revalidate hits) onto a
+// pipeline's raw/error channels instead of only updating its own cache. wg
+// tracks refreshes outstanding for this run: the caller must Wait() on it
+// before closing ch/errCh, since a refresh can still be in flight after the
+// fetch/parse stages that spawned it have otherwise finished. A pipeline
+// driving RunStream wires this automatically when ag.fetcher implements it,
+// so a caller never has to reach past the Fetcher interface to get
+// refreshes flowing.
+type RefreshWirer interface {
+	WithRefreshChannels(ch chan<- model.RawResponse, errCh chan<- model.PipelineError, wg *sync.WaitGroup)
+}
+
+// RetryPolicy controls how a Fetcher retries a failed request.
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: DefaultMaxRetries,
+	BaseDelay:  DefaultBaseDelay,
+	MaxDelay:   DefaultMaxDelay,
+}
+
+// backoff returns the delay before retry attempt n (0-indexed), using
+// exponential backoff with full jitter, capped at MaxDelay.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay * time.Duration(1<<attempt)
+	if d > p.MaxDelay || d <= 0 {
+		d = p.MaxDelay
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// RateLimiter is a simple token-bucket limiter shared across endpoints so the
+// aggregator doesn't hammer jsonplaceholder when fetching many resources.
+type RateLimiter struct {
+	tokens    chan struct{}
+	stop      chan struct{}
+	unlimited bool
+}
+
+// NewRateLimiter returns a limiter that refills burst tokens ratePerSecond
+// times a second. A ratePerSecond <= 0 means "no rate limiting": Wait always
+// returns immediately instead of dividing by zero.
+func NewRateLimiter(ratePerSecond, burst int) *RateLimiter {
+	if ratePerSecond <= 0 {
+		return &RateLimiter{unlimited: true, stop: make(chan struct{})}
+	}
+
+	rl := &RateLimiter{
+		tokens: make(chan struct{}, burst),
+		stop:   make(chan struct{}),
+	}
+	for range burst {
+		rl.tokens <- struct{}{}
+	}
+
+	interval := time.Second / time.Duration(ratePerSecond)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				select {
+				case rl.tokens <- struct{}{}:
+				default:
+				}
+			case <-rl.stop:
+				return
+			}
+		}
+	}()
+
+	return rl
+}
+
+func (rl *RateLimiter) Wait(ctx context.Context) error {
+	if rl.unlimited {
+		return nil
+	}
+	select {
+	case <-rl.tokens:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (rl *RateLimiter) Close() {
+	close(rl.stop)
+}
+
+// HTTPFetcher is the default Fetcher, wrapping an *http.Client with retries,
+// exponential backoff + jitter, Retry-After handling, and rate limiting.
+type HTTPFetcher struct {
+	client  *http.Client
+	retry   RetryPolicy
+	limiter *RateLimiter
+}
+
+func NewHTTPFetcher(client *http.Client, retry RetryPolicy, limiter *RateLimiter) *HTTPFetcher {
+	return &HTTPFetcher{client: client, retry: retry, limiter: limiter}
+}
+
+func (f *HTTPFetcher) Fetch(ctx context.Context, endpoint string) ([]byte, error) {
+	var lastErr error
+	var lastKind model.ErrorKind
+
+	for attempt := 0; attempt <= f.retry.MaxRetries; attempt++ {
+		if attempt > 0 {
+			delay := f.retry.backoff(attempt - 1)
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return nil, classifiedError{err: ctx.Err(), kind: model.ErrKindTimeout}
+			}
+		}
+
+		if f.limiter != nil {
+			if err := f.limiter.Wait(ctx); err != nil {
+				return nil, classifiedError{err: err, kind: model.ErrKindTimeout}
+			}
+		}
+
+		data, kind, err := f.attempt(ctx, endpoint)
+		if err == nil {
+			return data, nil
+		}
+
+		lastErr, lastKind = err, kind
+		if !kind.Retryable() {
+			break
+		}
+	}
+
+	return nil, classifiedError{err: lastErr, kind: lastKind}
+}
+
+func (f *HTTPFetcher) attempt(ctx context.Context, endpoint string) ([]byte, model.ErrorKind, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, model.ErrKindPermanentHTTP, err
+	}
+
+	res, err := f.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, model.ErrKindTimeout, ctx.Err()
+		}
+		var netErr interface{ Timeout() bool }
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return nil, model.ErrKindTimeout, err
+		}
+		return nil, model.ErrKindTransientHTTP, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusTooManyRequests || res.StatusCode == http.StatusServiceUnavailable {
+		if d, ok := retryAfterDelay(res.Header.Get("Retry-After")); ok {
+			select {
+			case <-time.After(d):
+			case <-ctx.Done():
+				return nil, model.ErrKindTimeout, ctx.Err()
+			}
+		}
+		return nil, model.ErrKindTransientHTTP, fmt.Errorf("HTTP %d", res.StatusCode)
+	}
+	if res.StatusCode >= 500 {
+		return nil, model.ErrKindTransientHTTP, fmt.Errorf("HTTP %d", res.StatusCode)
+	}
+	if res.StatusCode >= 400 {
+		return nil, model.ErrKindPermanentHTTP, fmt.Errorf("HTTP %d", res.StatusCode)
+	}
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, model.ErrKindDecode, err
+	}
+
+	return data, 0, nil
+}
+
+// retryAfterDelay parses a Retry-After header expressed in seconds. HTTP also
+// allows an HTTP-date form, which jsonplaceholder never sends, so it's not handled here.
+func retryAfterDelay(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	secs, err := strconv.Atoi(header)
+	if err != nil || secs < 0 {
+		return 0, false
+	}
+	return time.Duration(secs) * time.Second, true
+}
+
+// classifiedError pairs an error with the ErrorKind a caller should record on
+// a model.PipelineError, so retry decisions survive the Fetch boundary.
+type classifiedError struct {
+	err  error
+	kind model.ErrorKind
+}
+
+func (c classifiedError) Error() string { return c.err.Error() }
+func (c classifiedError) Unwrap() error { return c.err }
+
+// FetchInto runs fetcher.Fetch for endpoint and reports the result onto ch,
+// or a classified model.PipelineError onto errCh on failure. It's the shape
+// the fetch stage of a pipeline drives concurrently, one goroutine per
+// endpoint.
+func FetchInto(ctx context.Context, f Fetcher, endpoint string, ch chan<- model.RawResponse, errCh chan<- model.PipelineError, wg *sync.WaitGroup, stage string) {
+	defer wg.Done()
+
+	data, err := f.Fetch(ctx, endpoint)
+	if err != nil {
+		kind := model.ErrKindUnknown
+		var ce classifiedError
+		if errors.As(err, &ce) {
+			kind = ce.kind
+		}
+		select {
+		case errCh <- model.PipelineError{
+			Stage:     stage,
+			Endpoint:  endpoint,
+			Error:     err,
+			Kind:      kind,
+			Timestamp: time.Now(),
+		}:
+		case <-ctx.Done():
+		}
+		return
+	}
+
+	select {
+	case ch <- model.RawResponse{Endpoint: endpoint, Data: data}:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,117 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Guizzs26/go-jsonplaceholder-aggregator/model"
+)
+
+// mockFetcher is the Fetcher mock the package doc comment promises callers
+// can plug in; it replays responses (or errors) by call count, optionally
+// sleeping first to simulate a slow endpoint.
+type mockFetcher struct {
+	calls     int
+	responses [][]byte
+	errs      []error
+	delay     time.Duration
+}
+
+func (m *mockFetcher) Fetch(ctx context.Context, endpoint string) ([]byte, error) {
+	if m.delay > 0 {
+		time.Sleep(m.delay)
+	}
+	i := m.calls
+	m.calls++
+	if i < len(m.errs) && m.errs[i] != nil {
+		return nil, m.errs[i]
+	}
+	if i < len(m.responses) {
+		return m.responses[i], nil
+	}
+	return nil, errors.New("mockFetcher: no more responses queued")
+}
+
+func TestRateLimiterUnlimitedDoesNotDivideByZero(t *testing.T) {
+	for _, rate := range []int{0, -1} {
+		rl := NewRateLimiter(rate, 1)
+		defer rl.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		if err := rl.Wait(ctx); err != nil {
+			t.Errorf("NewRateLimiter(%d, 1).Wait: got %v, want nil", rate, err)
+		}
+	}
+}
+
+func TestRateLimiterLimitsBurst(t *testing.T) {
+	rl := NewRateLimiter(1000, 2)
+	defer rl.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for i := 0; i < 2; i++ {
+		if err := rl.Wait(ctx); err != nil {
+			t.Fatalf("Wait %d: %v", i, err)
+		}
+	}
+	// The burst is spent; the ticker should still refill within the timeout.
+	if err := rl.Wait(ctx); err != nil {
+		t.Fatalf("Wait after burst exhausted: %v", err)
+	}
+}
+
+func TestFetchIntoReportsMockFetcherResult(t *testing.T) {
+	m := &mockFetcher{responses: [][]byte{[]byte(`[]`)}}
+	ch := make(chan model.RawResponse, 1)
+	errCh := make(chan model.PipelineError, 1)
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	FetchInto(context.Background(), m, "https://example.test/users", ch, errCh, &wg, "fetch")
+	wg.Wait()
+
+	select {
+	case raw := <-ch:
+		if string(raw.Data) != `[]` || raw.Endpoint != "https://example.test/users" {
+			t.Errorf("unexpected RawResponse: %+v", raw)
+		}
+	default:
+		t.Fatal("expected a RawResponse on ch, got none")
+	}
+}
+
+func TestFetchIntoReportsMockFetcherError(t *testing.T) {
+	m := &mockFetcher{errs: []error{errors.New("boom")}}
+	ch := make(chan model.RawResponse, 1)
+	errCh := make(chan model.PipelineError, 1)
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	FetchInto(context.Background(), m, "https://example.test/users", ch, errCh, &wg, "fetch")
+	wg.Wait()
+
+	select {
+	case perr := <-errCh:
+		if perr.Stage != "fetch" || perr.Endpoint != "https://example.test/users" {
+			t.Errorf("unexpected PipelineError: %+v", perr)
+		}
+	default:
+		t.Fatal("expected a PipelineError on errCh, got none")
+	}
+}
+
+func TestRetryPolicyBackoffCappedAtMaxDelay(t *testing.T) {
+	p := RetryPolicy{BaseDelay: time.Second, MaxDelay: 2 * time.Second}
+	for attempt := 0; attempt < 5; attempt++ {
+		if d := p.backoff(attempt); d > p.MaxDelay {
+			t.Errorf("backoff(%d) = %v, want <= %v", attempt, d, p.MaxDelay)
+		}
+	}
+}
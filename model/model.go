@@ -0,0 +1,118 @@
+// Package model holds the plain data types shared across the aggregator's
+// fetch, parse, and aggregate stages.
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrDeadlineExceeded is the error recorded on a PipelineError when a stage
+// is cut short by a per-stage deadline rather than by an endpoint-specific
+// failure.
+var ErrDeadlineExceeded = errors.New("pipeline: stage deadline exceeded")
+
+type User struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type Post struct {
+	ID     int    `json:"id"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	UserID int    `json:"userId"`
+}
+
+type Comment struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+	Body   string `json:"body"`
+	PostID int    `json:"postId"`
+}
+
+type Album struct {
+	ID     int    `json:"id"`
+	Title  string `json:"title"`
+	UserID int    `json:"userId"`
+}
+
+type Photo struct {
+	ID           int    `json:"id"`
+	Title        string `json:"title"`
+	Url          string `json:"url"`
+	ThumbnailUrl string `json:"thumbnailUrl"`
+	AlbumID      int    `json:"albumId"`
+}
+
+type Todo struct {
+	ID        int    `json:"id"`
+	Title     string `json:"title"`
+	Completed bool   `json:"completed"`
+	UserID    int    `json:"userId"`
+}
+
+type EnrichedUser struct {
+	ID           int
+	Name         string
+	Email        string
+	PostCount    int
+	AlbumCount   int
+	TodoCount    int
+	CommentCount int
+	PhotoCount   int
+}
+
+// RawResponse is the unparsed payload returned by a fetch of an endpoint.
+type RawResponse struct {
+	Endpoint string
+	Data     []byte
+}
+
+// ParsedData is a RawResponse after it has been decoded into its Go type.
+type ParsedData struct {
+	Data     any
+	Endpoint string
+	Type     string
+}
+
+type ErrorKind int
+
+const (
+	ErrKindUnknown ErrorKind = iota
+	ErrKindTimeout
+	ErrKindTransientHTTP
+	ErrKindPermanentHTTP
+	ErrKindDecode
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrKindTimeout:
+		return "timeout"
+	case ErrKindTransientHTTP:
+		return "transient_http"
+	case ErrKindPermanentHTTP:
+		return "permanent_http"
+	case ErrKindDecode:
+		return "decode"
+	default:
+		return "unknown"
+	}
+}
+
+// Retryable reports whether a failure of this kind is worth retrying.
+func (k ErrorKind) Retryable() bool {
+	return k == ErrKindTimeout || k == ErrKindTransientHTTP
+}
+
+// PipelineError records a failure at a specific stage of the pipeline.
+type PipelineError struct {
+	Stage     string
+	Endpoint  string
+	Error     error
+	Kind      ErrorKind
+	Timestamp time.Time
+}
@@ -0,0 +1,15 @@
+// Package parser decodes the raw bytes fetched for an endpoint into typed
+// Go values.
+package parser
+
+import "encoding/json"
+
+// Decode unmarshals data as a JSON array of T, boxed as any so it can be
+// used as the parseFn registered against an endpoint.
+func Decode[T any](data []byte) (any, error) {
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,166 @@
+// Package server exposes an AggregatedData snapshot over HTTP, with content
+// negotiation between JSON, NDJSON, and CSV.
+package server
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	aggregator "github.com/Guizzs26/go-jsonplaceholder-aggregator"
+	"github.com/Guizzs26/go-jsonplaceholder-aggregator/model"
+)
+
+// Server serves AggregatedData computed by running ag's pipeline once per
+// request. jsonplaceholder's dataset is small and static, so this keeps
+// responses fresh without needing a separate refresh loop.
+type Server struct {
+	ag *aggregator.Aggregator
+}
+
+func New(ag *aggregator.Aggregator) *Server {
+	return &Server{ag: ag}
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users", s.handleUsers)
+	mux.HandleFunc("GET /users/{id}/enriched", s.handleUserEnriched)
+	mux.HandleFunc("GET /posts/{id}/comments", s.handlePostComments)
+	return mux
+}
+
+func (s *Server) handleUsers(w http.ResponseWriter, r *http.Request) {
+	ad, err := s.ag.Run(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	users := ad.GetEnrichedUsers()
+
+	switch negotiate(r.Header.Get("Accept")) {
+	case formatNDJSON:
+		writeNDJSON(w, users)
+	case formatCSV:
+		writeCSV(w, users)
+	default:
+		writeJSON(w, users)
+	}
+}
+
+func (s *Server) handleUserEnriched(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid user id", http.StatusBadRequest)
+		return
+	}
+
+	ad, err := s.ag.Run(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	for _, u := range ad.GetEnrichedUsers() {
+		if u.ID == id {
+			writeJSON(w, u)
+			return
+		}
+	}
+	http.Error(w, "user not found", http.StatusNotFound)
+}
+
+func (s *Server) handlePostComments(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid post id", http.StatusBadRequest)
+		return
+	}
+
+	ad, err := s.ag.Run(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	writeJSON(w, ad.Comments[id])
+}
+
+type format int
+
+const (
+	formatJSON format = iota
+	formatNDJSON
+	formatCSV
+)
+
+func negotiate(accept string) format {
+	switch {
+	case strings.Contains(accept, "application/x-ndjson"), strings.Contains(accept, "application/ndjson"):
+		return formatNDJSON
+	case strings.Contains(accept, "text/csv"):
+		return formatCSV
+	default:
+		return formatJSON
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeNDJSON streams one enriched user per line, flushing after each write.
+// This is a framing choice for large responses, not a mechanism for
+// delivering users before the pipeline finishes: every EnrichedUser field
+// folds in data from all six endpoints (Users, Posts, Comments, Albums,
+// Photos, Todos), and jsonplaceholder returns each of those as a single
+// JSON array rather than a per-user stream, so no user's row is complete -
+// and every user becomes complete at the same instant - until the last of
+// those six batches has parsed. Genuinely progressive per-user delivery
+// would need per-user data from the source API, which jsonplaceholder
+// doesn't offer; handleUsers calls Run the same way the JSON/CSV paths do.
+func writeNDJSON(w http.ResponseWriter, users []model.EnrichedUser) {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	enc := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+
+	for _, u := range users {
+		if err := enc.Encode(u); err != nil {
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+func writeCSV(w http.ResponseWriter, users []model.EnrichedUser) {
+	w.Header().Set("Content-Type", "text/csv")
+
+	cw := csv.NewWriter(w)
+	defer cw.Flush()
+
+	_ = cw.Write([]string{"id", "name", "email", "posts", "albums", "todos", "comments", "photos"})
+	for _, u := range users {
+		_ = cw.Write([]string{
+			strconv.Itoa(u.ID),
+			u.Name,
+			u.Email,
+			strconv.Itoa(u.PostCount),
+			strconv.Itoa(u.AlbumCount),
+			strconv.Itoa(u.TodoCount),
+			strconv.Itoa(u.CommentCount),
+			strconv.Itoa(u.PhotoCount),
+		})
+	}
+}
+
+// ListenAndServe is a small convenience wrapper so callers don't need to
+// import net/http just to start the server.
+func ListenAndServe(addr string, ag *aggregator.Aggregator) error {
+	return http.ListenAndServe(addr, New(ag).Handler())
+}